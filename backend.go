@@ -0,0 +1,59 @@
+package ezdb
+
+import "errors"
+
+// errBucketMissing is returned internally when a write targets a bucket
+// that was never created via Backend.Bucket. NewRef always creates its
+// bucket up front, so callers going through Ref should never see this.
+var errBucketMissing = errors.New("ezdb: bucket does not exist")
+
+// Backend is the minimal storage engine contract DB relies on. New's
+// default is backed by bbolt (see boltbackend.go); WithBackend swaps in
+// any other implementation, such as the in-memory MemBackend used by
+// tests that don't want to touch the filesystem.
+type Backend interface {
+	// Bucket returns the named keyspace, creating it if it doesn't
+	// already exist.
+	Bucket(name string) error
+	// BeginTx starts a transaction. writable selects a read-write
+	// transaction (only one may be open at a time) versus a read-only
+	// transaction pinned to a stable snapshot.
+	BeginTx(writable bool) (BackendTx, error)
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// BackendTx is a single transaction against a Backend.
+type BackendTx interface {
+	// Bucket returns a handle onto the named keyspace as seen by this
+	// transaction. It never returns nil; reads, deletes and cursors
+	// against a bucket that doesn't exist behave as if it were empty. A
+	// Put to such a bucket fails with errBucketMissing on BoltBackend,
+	// while MemBackend creates the bucket when the transaction commits.
+	Bucket(name string) BackendBucket
+	Writable() bool
+	Commit() error
+	Rollback() error
+}
+
+// BackendBucket is a single named keyspace as seen by a BackendTx.
+type BackendBucket interface {
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	// DeleteAll removes every entry in the bucket without removing the
+	// bucket itself.
+	DeleteAll() error
+	Cursor() BackendCursor
+}
+
+// BackendCursor walks a BackendBucket in encoded-key order. It mirrors
+// bbolt's own Cursor shape: First/Last/Next/Prev/Seek each return a nil
+// key once there is nothing more to return.
+type BackendCursor interface {
+	First() (k, v []byte)
+	Last() (k, v []byte)
+	Next() (k, v []byte)
+	Prev() (k, v []byte)
+	Seek(key []byte) (k, v []byte)
+}
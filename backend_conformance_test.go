@@ -0,0 +1,38 @@
+package ezdb_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kokizzu/ezdb"
+	"github.com/kokizzu/ezdb/dbtest"
+)
+
+// TestBackends_Conformance runs the shared dbtest conformance suite
+// against both the in-memory and on-disk backends, so that MemBackend is
+// guaranteed to behave identically to the bbolt-backed default. This
+// lives in the ezdb_test package (rather than ezdb) because dbtest itself
+// imports ezdb, and an internal test file can't import anything that
+// imports back into its own package.
+func TestBackends_Conformance(t *testing.T) {
+	t.Run("Mem", func(t *testing.T) {
+		dbtest.RunConformance(t, func() ezdb.Backend {
+			return ezdb.NewMemBackend()
+		})
+	})
+
+	t.Run("Bolt", func(t *testing.T) {
+		dir := t.TempDir()
+		n := 0
+		dbtest.RunConformance(t, func() ezdb.Backend {
+			n++
+			b, err := ezdb.NewBoltBackend(filepath.Join(dir, fmt.Sprintf("bolt-%d.db", n)), 2*time.Second)
+			if err != nil {
+				t.Fatalf("NewBoltBackend: %v", err)
+			}
+			return b
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package ezdb
+
+import "fmt"
+
+// PutBatch writes all of keys/values in a single transaction, instead of
+// paying one Update's commit overhead per entry. keys and values must be
+// the same length.
+func (r *Ref[K, V]) PutBatch(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("ezdb: PutBatch: %d keys but %d values", len(keys), len(values))
+	}
+	return r.db.Update(func(tx *Tx) error {
+		b := tx.backend.Bucket(r.name)
+		for i := range keys {
+			kb, err := encodeKey(&keys[i])
+			if err != nil {
+				return err
+			}
+			vb, err := encodeValue(&values[i])
+			if err != nil {
+				return err
+			}
+			if err := b.Put(kb, vb); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteBatch removes all of keys in a single transaction.
+func (r *Ref[K, V]) DeleteBatch(keys []K) error {
+	return r.db.Update(func(tx *Tx) error {
+		b := tx.backend.Bucket(r.name)
+		for i := range keys {
+			kb, err := encodeKey(&keys[i])
+			if err != nil {
+				return err
+			}
+			if err := b.Delete(kb); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// BulkDeleteAll wipes every entry in the ref's keyspace. This is cheaper
+// than deleting keys one at a time when the ref is being used as a
+// scratch table and needs a full truncate, e.g. between test runs.
+func (r *Ref[K, V]) BulkDeleteAll() error {
+	return r.db.Update(func(tx *Tx) error {
+		return tx.backend.Bucket(r.name).DeleteAll()
+	})
+}
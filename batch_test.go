@@ -0,0 +1,110 @@
+package ezdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestRef_PutBatchDeleteBatch covers writing and removing many keys in a
+// single transaction.
+func TestRef_PutBatchDeleteBatch(t *testing.T) {
+	os.RemoveAll("testdb_batch")
+	defer os.RemoveAll("testdb_batch")
+
+	db, err := New("testdb_batch")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[int, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	const n = 500
+	keys := make([]int, n)
+	values := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = i
+		values[i] = fmt.Sprintf("value-%d", i)
+	}
+
+	if err := ref.PutBatch(keys, values); err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		out, err := ref.Get(&keys[i])
+		if err != nil {
+			t.Fatalf("Get(%d): %v", i, err)
+		}
+		if *out != values[i] {
+			t.Errorf("key %d: expected %q, got %q", i, values[i], *out)
+		}
+	}
+
+	toDelete := keys[:n/2]
+	if err := ref.DeleteBatch(toDelete); err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+
+	for i := 0; i < n/2; i++ {
+		if _, err := ref.Get(&keys[i]); err != ErrNotFound {
+			t.Errorf("key %d: expected ErrNotFound after DeleteBatch, got %v", i, err)
+		}
+	}
+	for i := n / 2; i < n; i++ {
+		if _, err := ref.Get(&keys[i]); err != nil {
+			t.Errorf("key %d: expected it to survive DeleteBatch, got %v", i, err)
+		}
+	}
+}
+
+// TestRef_BulkDeleteAll covers truncating a ref's entire keyspace.
+func TestRef_BulkDeleteAll(t *testing.T) {
+	os.RemoveAll("testdb_bulk_delete")
+	defer os.RemoveAll("testdb_bulk_delete")
+
+	db, err := New("testdb_bulk_delete")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[int, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		v := i * 2
+		if err := ref.Put(&i, &v); err != nil {
+			t.Fatalf("Put(%d): %v", i, err)
+		}
+	}
+
+	if err := ref.BulkDeleteAll(); err != nil {
+		t.Fatalf("BulkDeleteAll: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if _, err := ref.Get(&i); err != ErrNotFound {
+			t.Errorf("key %d: expected ErrNotFound after BulkDeleteAll, got %v", i, err)
+		}
+	}
+
+	// The ref must still be usable after a truncate.
+	k, v := 1, 2
+	if err := ref.Put(&k, &v); err != nil {
+		t.Fatalf("Put after BulkDeleteAll: %v", err)
+	}
+	out, err := ref.Get(&k)
+	if err != nil {
+		t.Fatalf("Get after BulkDeleteAll: %v", err)
+	}
+	if *out != v {
+		t.Errorf("expected %d, got %d", v, *out)
+	}
+}
@@ -0,0 +1,127 @@
+package ezdb
+
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltBackend is the default Backend, persisting to a single file on
+// disk via bbolt.
+type BoltBackend struct {
+	db *bolt.DB
+}
+
+// NewBoltBackend opens (creating if necessary) the bbolt file at path.
+func NewBoltBackend(path string, timeout time.Duration) (*BoltBackend, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		return nil, err
+	}
+	return &BoltBackend{db: db}, nil
+}
+
+func (b *BoltBackend) Bucket(name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(name))
+		return err
+	})
+}
+
+func (b *BoltBackend) BeginTx(writable bool) (BackendTx, error) {
+	tx, err := b.db.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return &boltBackendTx{tx: tx}, nil
+}
+
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
+
+type boltBackendTx struct {
+	tx *bolt.Tx
+}
+
+func (t *boltBackendTx) Bucket(name string) BackendBucket {
+	b := t.tx.Bucket([]byte(name))
+	if b == nil {
+		return emptyBucket{}
+	}
+	return boltBackendBucket{b: b, tx: t.tx, name: []byte(name)}
+}
+
+func (t *boltBackendTx) Writable() bool { return t.tx.Writable() }
+func (t *boltBackendTx) Commit() error  { return t.tx.Commit() }
+func (t *boltBackendTx) Rollback() error {
+	err := t.tx.Rollback()
+	if err == bolt.ErrTxClosed {
+		return nil
+	}
+	return err
+}
+
+type boltBackendBucket struct {
+	b    *bolt.Bucket
+	tx   *bolt.Tx
+	name []byte
+}
+
+func (b boltBackendBucket) Get(key []byte) []byte {
+	v := b.b.Get(key)
+	if v == nil {
+		return nil
+	}
+	return append([]byte(nil), v...)
+}
+
+func (b boltBackendBucket) Put(key, value []byte) error {
+	return b.b.Put(key, value)
+}
+
+func (b boltBackendBucket) Delete(key []byte) error {
+	return b.b.Delete(key)
+}
+
+func (b boltBackendBucket) DeleteAll() error {
+	if err := b.tx.DeleteBucket(b.name); err != nil {
+		return err
+	}
+	_, err := b.tx.CreateBucket(b.name)
+	return err
+}
+
+func (b boltBackendBucket) Cursor() BackendCursor {
+	return boltBackendCursor{c: b.b.Cursor()}
+}
+
+type boltBackendCursor struct {
+	c *bolt.Cursor
+}
+
+func (c boltBackendCursor) First() (k, v []byte) { return c.c.First() }
+func (c boltBackendCursor) Last() (k, v []byte)  { return c.c.Last() }
+func (c boltBackendCursor) Next() (k, v []byte)  { return c.c.Next() }
+func (c boltBackendCursor) Prev() (k, v []byte)  { return c.c.Prev() }
+func (c boltBackendCursor) Seek(key []byte) (k, v []byte) {
+	return c.c.Seek(key)
+}
+
+// emptyBucket is returned for a bucket name that doesn't exist within a
+// given transaction; every operation behaves as if the bucket were empty.
+type emptyBucket struct{}
+
+func (emptyBucket) Get(key []byte) []byte       { return nil }
+func (emptyBucket) Put(key, value []byte) error { return errBucketMissing }
+func (emptyBucket) Delete(key []byte) error     { return nil }
+func (emptyBucket) DeleteAll() error            { return nil }
+func (emptyBucket) Cursor() BackendCursor       { return emptyCursor{} }
+
+type emptyCursor struct{}
+
+func (emptyCursor) First() (k, v []byte)          { return nil, nil }
+func (emptyCursor) Last() (k, v []byte)           { return nil, nil }
+func (emptyCursor) Next() (k, v []byte)           { return nil, nil }
+func (emptyCursor) Prev() (k, v []byte)           { return nil, nil }
+func (emptyCursor) Seek(key []byte) (k, v []byte) { return nil, nil }
@@ -0,0 +1,110 @@
+package ezdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+)
+
+// encodeKey turns a key into its on-disk byte representation. For the
+// integer and string kinds used as map keys in practice, the encoding is
+// chosen so that byte-lexicographic order on the result matches the
+// natural order of the key — this is what lets Cursor range scans (see
+// cursor.go) behave the way callers expect. Any other type falls back to
+// gob, which carries no ordering guarantee.
+func encodeKey[K any](key *K) ([]byte, error) {
+	switch k := any(*key).(type) {
+	case string:
+		return []byte(k), nil
+	case []byte:
+		return k, nil
+	case int:
+		return encodeOrderedInt(int64(k)), nil
+	case int32:
+		return encodeOrderedInt(int64(k)), nil
+	case int64:
+		return encodeOrderedInt(k), nil
+	case uint:
+		return encodeOrderedUint(uint64(k)), nil
+	case uint32:
+		return encodeOrderedUint(uint64(k)), nil
+	case uint64:
+		return encodeOrderedUint(k), nil
+	default:
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(key); err != nil {
+			return nil, fmt.Errorf("ezdb: encode key: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// encodeOrderedInt encodes a signed integer as 8 big-endian bytes with the
+// sign bit flipped, so that two's-complement ordering becomes unsigned
+// byte-lexicographic ordering.
+func encodeOrderedInt(v int64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(v)^(1<<63))
+	return buf
+}
+
+func encodeOrderedUint(v uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, v)
+	return buf
+}
+
+func encodeValue[V any](value *V) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, fmt.Errorf("ezdb: encode value: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeKey is the inverse of encodeKey. It must stay in sync with it key
+// for key: every case encodeKey special-cases has to round-trip here too,
+// since Cursor hands decoded keys back to callers during a walk.
+func decodeKey[K any](data []byte) (*K, error) {
+	var k K
+	switch p := any(&k).(type) {
+	case *string:
+		*p = string(data)
+	case *[]byte:
+		*p = append([]byte(nil), data...)
+	case *int:
+		*p = int(decodeOrderedInt(data))
+	case *int32:
+		*p = int32(decodeOrderedInt(data))
+	case *int64:
+		*p = decodeOrderedInt(data)
+	case *uint:
+		*p = uint(decodeOrderedUint(data))
+	case *uint32:
+		*p = uint32(decodeOrderedUint(data))
+	case *uint64:
+		*p = decodeOrderedUint(data)
+	default:
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&k); err != nil {
+			return nil, fmt.Errorf("ezdb: decode key: %w", err)
+		}
+	}
+	return &k, nil
+}
+
+func decodeOrderedInt(data []byte) int64 {
+	return int64(binary.BigEndian.Uint64(data) ^ (1 << 63))
+}
+
+func decodeOrderedUint(data []byte) uint64 {
+	return binary.BigEndian.Uint64(data)
+}
+
+func decodeValue[V any](data []byte) (*V, error) {
+	var value V
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, fmt.Errorf("ezdb: decode value: %w", err)
+	}
+	return &value, nil
+}
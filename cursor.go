@@ -0,0 +1,268 @@
+package ezdb
+
+import "bytes"
+
+// IterOptions bounds and shapes a range scan over a Ref's keyspace.
+//
+// Bounds are matched against the encoded key bytes produced by encodeKey,
+// so lexicographic order on those bytes must match the order callers
+// expect of K: this holds for string keys (compared byte-for-byte) and
+// for the fixed-width int/uint kinds encodeKey special-cases (encoded
+// big-endian, with signed kinds sign-flipped so two's-complement order
+// becomes unsigned byte order). Any other K falls back to gob, which has
+// no defined byte order, so Start/End/Prefix bounds are meaningless for
+// such keys.
+type IterOptions[K any] struct {
+	// Prefix, if set, restricts the scan to keys whose encoded bytes
+	// start with the encoding of *Prefix.
+	Prefix *K
+	// Start and End bound the scan to encoded keys in [Start, End). Either
+	// may be nil to leave that side unbounded. They compose with Prefix.
+	Start *K
+	End   *K
+	// Reverse walks the range from its high end to its low end.
+	Reverse bool
+	// KeysOnly skips decoding values, leaving the *V passed to Iterate's
+	// callback nil. Useful for key-only sweeps where decoding the value
+	// would be wasted work.
+	KeysOnly bool
+}
+
+// Cursor walks a Ref's keyspace in encoded-key order within its own
+// implicit read snapshot: the snapshot is taken when the Cursor is opened
+// and held until Close, so a concurrent Update can never invalidate a
+// walk in progress (the same MVCC guarantee View relies on, see tx.go).
+// Close must be called exactly once when the caller is done.
+type Cursor[K any, V any] struct {
+	db      *DB
+	backend BackendTx
+	cursor  BackendCursor
+	opts    IterOptions[K]
+
+	prefix []byte
+	lower  []byte
+	upper  []byte
+}
+
+// Cursor opens a Cursor over the ref's entire keyspace.
+func (r *Ref[K, V]) Cursor() (*Cursor[K, V], error) {
+	return r.CursorWithOptions(IterOptions[K]{})
+}
+
+// CursorWithOptions opens a Cursor bounded by opts.
+func (r *Ref[K, V]) CursorWithOptions(opts IterOptions[K]) (*Cursor[K, V], error) {
+	r.db.acquireReader()
+	btx, err := r.db.backend.BeginTx(false)
+	if err != nil {
+		r.db.releaseReader()
+		return nil, err
+	}
+
+	c := &Cursor[K, V]{
+		db:      r.db,
+		backend: btx,
+		cursor:  btx.Bucket(r.name).Cursor(),
+		opts:    opts,
+	}
+	if opts.Prefix != nil {
+		if c.prefix, err = encodeKey(opts.Prefix); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if opts.Start != nil {
+		if c.lower, err = encodeKey(opts.Start); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if opts.End != nil {
+		if c.upper, err = encodeKey(opts.End); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Close releases the cursor's underlying read snapshot.
+func (c *Cursor[K, V]) Close() error {
+	defer c.db.releaseReader()
+	return c.backend.Rollback()
+}
+
+// inRange reports whether encoded key kb satisfies the cursor's Prefix,
+// Start and End bounds.
+func (c *Cursor[K, V]) inRange(kb []byte) bool {
+	if kb == nil {
+		return false
+	}
+	if c.prefix != nil && !bytes.HasPrefix(kb, c.prefix) {
+		return false
+	}
+	if c.lower != nil && bytes.Compare(kb, c.lower) < 0 {
+		return false
+	}
+	if c.upper != nil && bytes.Compare(kb, c.upper) >= 0 {
+		return false
+	}
+	return true
+}
+
+func (c *Cursor[K, V]) decode(kb, vb []byte) (*K, *V, error) {
+	if kb == nil {
+		return nil, nil, nil
+	}
+	k, err := decodeKey[K](kb)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.opts.KeysOnly {
+		return k, nil, nil
+	}
+	v, err := decodeValue[V](vb)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, v, nil
+}
+
+// First seeks to the lowest key within bounds. It returns a nil key (and
+// no error) once the range is exhausted.
+func (c *Cursor[K, V]) First() (*K, *V, error) {
+	// Prefix and Start compose: when both are set, the scan must begin at
+	// whichever bound sorts higher, or a Start past the prefix's own
+	// minimum key would seek too early and fail inRange on the first try.
+	lower := c.lower
+	if c.prefix != nil && (lower == nil || bytes.Compare(c.prefix, lower) > 0) {
+		lower = c.prefix
+	}
+
+	var kb, vb []byte
+	if lower != nil {
+		kb, vb = c.cursor.Seek(lower)
+	} else {
+		kb, vb = c.cursor.First()
+	}
+	if !c.inRange(kb) {
+		return nil, nil, nil
+	}
+	return c.decode(kb, vb)
+}
+
+// Last seeks to the highest key within bounds.
+func (c *Cursor[K, V]) Last() (*K, *V, error) {
+	// Prefix and End compose: a Prefix also bounds the range from above
+	// via its successor, so the seek target is whichever of End and the
+	// successor sorts lower. Last (and Reverse iteration) then lands on
+	// the prefix's actual last key instead of on a key past the prefix.
+	upper := c.upper
+	if c.prefix != nil {
+		if succ := prefixSuccessor(c.prefix); succ != nil && (upper == nil || bytes.Compare(succ, upper) < 0) {
+			upper = succ
+		}
+	}
+
+	var kb, vb []byte
+	if upper != nil {
+		// Seek lands at or after upper; step back once since upper is
+		// exclusive.
+		kb, vb = c.cursor.Seek(upper)
+		if kb == nil {
+			kb, vb = c.cursor.Last()
+		} else {
+			kb, vb = c.cursor.Prev()
+		}
+	} else {
+		kb, vb = c.cursor.Last()
+	}
+	if !c.inRange(kb) {
+		return nil, nil, nil
+	}
+	return c.decode(kb, vb)
+}
+
+// prefixSuccessor returns the smallest byte slice greater than every
+// sequence with prefix b, or nil if none exists (b is all 0xFF bytes, so
+// nothing can sort after it; callers fall back to an unbounded seek in
+// that case).
+func prefixSuccessor(b []byte) []byte {
+	succ := append([]byte(nil), b...)
+	for i := len(succ) - 1; i >= 0; i-- {
+		if succ[i] != 0xFF {
+			succ[i]++
+			return succ[:i+1]
+		}
+	}
+	return nil
+}
+
+// Next advances to the next key within bounds.
+func (c *Cursor[K, V]) Next() (*K, *V, error) {
+	kb, vb := c.cursor.Next()
+	if !c.inRange(kb) {
+		return nil, nil, nil
+	}
+	return c.decode(kb, vb)
+}
+
+// Prev retreats to the previous key within bounds.
+func (c *Cursor[K, V]) Prev() (*K, *V, error) {
+	kb, vb := c.cursor.Prev()
+	if !c.inRange(kb) {
+		return nil, nil, nil
+	}
+	return c.decode(kb, vb)
+}
+
+// Seek moves to the first key within bounds that is greater than or equal
+// to key.
+func (c *Cursor[K, V]) Seek(key *K) (*K, *V, error) {
+	kb, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A key below Start or Prefix seeks to the first key within bounds,
+	// as First does.
+	if c.lower != nil && bytes.Compare(kb, c.lower) < 0 {
+		kb = c.lower
+	}
+	if c.prefix != nil && bytes.Compare(kb, c.prefix) < 0 {
+		kb = c.prefix
+	}
+	fkb, fvb := c.cursor.Seek(kb)
+	if !c.inRange(fkb) {
+		return nil, nil, nil
+	}
+	return c.decode(fkb, fvb)
+}
+
+// Iterate walks the ref's keyspace as bounded and ordered by opts, inside
+// an implicit read snapshot, calling fn for each entry. Iteration stops
+// early, returning fn's error, if fn returns a non-nil error.
+func (r *Ref[K, V]) Iterate(opts IterOptions[K], fn func(k *K, v *V) error) error {
+	c, err := r.CursorWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	step := c.Next
+	k, v, err := c.First()
+	if opts.Reverse {
+		step = c.Prev
+		k, v, err = c.Last()
+	}
+	for {
+		if err != nil {
+			return err
+		}
+		if k == nil {
+			return nil
+		}
+		if err := fn(k, v); err != nil {
+			return err
+		}
+		k, v, err = step()
+	}
+}
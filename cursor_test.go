@@ -0,0 +1,229 @@
+package ezdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestRef_IterateIntKeys covers range iteration, in both directions, over
+// fixed-width integer keys.
+func TestRef_IterateIntKeys(t *testing.T) {
+	os.RemoveAll("testdb_iter_int")
+	defer os.RemoveAll("testdb_iter_int")
+
+	db, err := New("testdb_iter_int")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[int, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	for i := -5; i <= 5; i++ {
+		v := fmt.Sprintf("v%d", i)
+		if err := ref.Put(&i, &v); err != nil {
+			t.Fatalf("Put(%d): %v", i, err)
+		}
+	}
+
+	var got []int
+	err = ref.Iterate(IterOptions[int]{}, func(k *int, v *string) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate: %v", err)
+	}
+	want := []int{-5, -4, -3, -2, -1, 0, 1, 2, 3, 4, 5}
+	if !intSliceEqual(got, want) {
+		t.Errorf("ascending order wrong: got %v, want %v", got, want)
+	}
+
+	got = nil
+	err = ref.Iterate(IterOptions[int]{Reverse: true}, func(k *int, v *string) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate reverse: %v", err)
+	}
+	wantRev := []int{5, 4, 3, 2, 1, 0, -1, -2, -3, -4, -5}
+	if !intSliceEqual(got, wantRev) {
+		t.Errorf("descending order wrong: got %v, want %v", got, wantRev)
+	}
+
+	start, end := -2, 3
+	got = nil
+	err = ref.Iterate(IterOptions[int]{Start: &start, End: &end}, func(k *int, v *string) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate bounded: %v", err)
+	}
+	wantBounded := []int{-2, -1, 0, 1, 2}
+	if !intSliceEqual(got, wantBounded) {
+		t.Errorf("bounded range wrong: got %v, want %v", got, wantBounded)
+	}
+}
+
+// TestRef_IterateStringKeysPrefix covers prefix iteration over string
+// keys, along with KeysOnly.
+func TestRef_IterateStringKeysPrefix(t *testing.T) {
+	os.RemoveAll("testdb_iter_string")
+	defer os.RemoveAll("testdb_iter_string")
+
+	db, err := New("testdb_iter_string")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[string, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	keys := []string{"apple", "apricot", "banana", "blueberry", "cherry"}
+	for i, k := range keys {
+		v := i
+		if err := ref.Put(&k, &v); err != nil {
+			t.Fatalf("Put(%q): %v", k, err)
+		}
+	}
+
+	prefix := "ap"
+	var got []string
+	err = ref.Iterate(IterOptions[string]{Prefix: &prefix}, func(k *string, v *int) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate prefix: %v", err)
+	}
+	want := []string{"apple", "apricot"}
+	if !stringSliceEqual(got, want) {
+		t.Errorf("prefix scan wrong: got %v, want %v", got, want)
+	}
+
+	got = nil
+	err = ref.Iterate(IterOptions[string]{Prefix: &prefix, Reverse: true}, func(k *string, v *int) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate prefix reverse: %v", err)
+	}
+	wantRev := []string{"apricot", "apple"}
+	if !stringSliceEqual(got, wantRev) {
+		t.Errorf("prefix reverse scan wrong: got %v, want %v", got, wantRev)
+	}
+
+	got = nil
+	start := "apricot"
+	err = ref.Iterate(IterOptions[string]{Prefix: &prefix, Start: &start}, func(k *string, v *int) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate prefix+start: %v", err)
+	}
+	wantFromStart := []string{"apricot"}
+	if !stringSliceEqual(got, wantFromStart) {
+		t.Errorf("prefix+start scan wrong: got %v, want %v", got, wantFromStart)
+	}
+
+	got = nil
+	err = ref.Iterate(IterOptions[string]{KeysOnly: true}, func(k *string, v *int) error {
+		if v != nil {
+			t.Errorf("KeysOnly: expected nil value for key %q, got %v", *k, *v)
+		}
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate KeysOnly: %v", err)
+	}
+	if len(got) != len(keys) {
+		t.Errorf("KeysOnly: expected %d keys, got %d", len(keys), len(got))
+	}
+}
+
+// TestRef_CursorExplicitWalk covers the lower-level Cursor API directly.
+func TestRef_CursorExplicitWalk(t *testing.T) {
+	os.RemoveAll("testdb_cursor")
+	defer os.RemoveAll("testdb_cursor")
+
+	db, err := New("testdb_cursor")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[int, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		v := i * i
+		if err := ref.Put(&i, &v); err != nil {
+			t.Fatalf("Put(%d): %v", i, err)
+		}
+	}
+
+	c, err := ref.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor: %v", err)
+	}
+	defer c.Close()
+
+	k, v, err := c.First()
+	if err != nil || k == nil || *k != 0 || *v != 0 {
+		t.Fatalf("First: k=%v v=%v err=%v", k, v, err)
+	}
+
+	seekTo := 5
+	k, v, err = c.Seek(&seekTo)
+	if err != nil || k == nil || *k != 5 || *v != 25 {
+		t.Fatalf("Seek(5): k=%v v=%v err=%v", k, v, err)
+	}
+
+	k, v, err = c.Next()
+	if err != nil || k == nil || *k != 6 || *v != 36 {
+		t.Fatalf("Next after Seek(5): k=%v v=%v err=%v", k, v, err)
+	}
+
+	k, v, err = c.Last()
+	if err != nil || k == nil || *k != 9 || *v != 81 {
+		t.Fatalf("Last: k=%v v=%v err=%v", k, v, err)
+	}
+}
+
+func intSliceEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+// Package ezdb is a small embedded key-value store giving typed, generic
+// access to named refs (buckets) without requiring callers to deal with
+// byte slices directly. It defaults to a bbolt-backed engine on disk, but
+// the engine itself is pluggable (see WithBackend).
+package ezdb
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// DB is a handle to an open database. A DB is safe for concurrent use by
+// multiple goroutines and is shared by every Ref opened against it.
+type DB struct {
+	backend    Backend
+	logger     zerolog.Logger
+	numReaders int
+	readSem    chan struct{}
+	writer     writerTracker
+
+	// refPath is set when this DB was obtained via Open rather than New,
+	// and names the registry entry Close should release a reference on
+	// instead of closing the backend directly.
+	refPath string
+}
+
+// New opens (creating if necessary) the database at path and returns a
+// handle to it. The returned DB must be closed with Close once it is no
+// longer needed. By default this opens a bbolt file at path; pass
+// WithBackend to use a different engine (e.g. MemBackend) instead, in
+// which case path is ignored.
+func New(path string, opts ...Option) (*DB, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	backend := o.backend
+	if backend == nil {
+		b, err := NewBoltBackend(path, o.timeout)
+		if err != nil {
+			return nil, fmt.Errorf("ezdb: open %s: %w", path, err)
+		}
+		backend = b
+	}
+
+	return &DB{
+		backend:    backend,
+		logger:     o.logger,
+		numReaders: o.numReaders,
+		readSem:    make(chan struct{}, o.numReaders),
+	}, nil
+}
+
+// Close releases the underlying backend. If db was obtained via Open
+// rather than New, Close instead releases this caller's reference on the
+// shared registry entry, and only closes the backend once the last
+// reference goes away.
+func (db *DB) Close() error {
+	if db.refPath != "" {
+		return closeRegistered(db.refPath)
+	}
+	return db.backend.Close()
+}
+
+// acquireReader blocks until a reader slot is available, enforcing the
+// WithNumReaders cap.
+func (db *DB) acquireReader() {
+	db.readSem <- struct{}{}
+}
+
+func (db *DB) releaseReader() {
+	<-db.readSem
+}
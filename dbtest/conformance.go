@@ -0,0 +1,191 @@
+// Package dbtest exercises any ezdb.Backend against a fixed conformance
+// suite, so that alternative engines (see ezdb.MemBackend) can be
+// verified to behave identically to the default on-disk one.
+package dbtest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kokizzu/ezdb"
+)
+
+// RunConformance exercises point operations, batches, iteration and
+// transaction isolation against a fresh backend produced by newBackend
+// for each subtest.
+func RunConformance(t *testing.T, newBackend func() ezdb.Backend) {
+	t.Run("PointOps", func(t *testing.T) { testPointOps(t, newBackend) })
+	t.Run("Batch", func(t *testing.T) { testBatch(t, newBackend) })
+	t.Run("Iteration", func(t *testing.T) { testIteration(t, newBackend) })
+	t.Run("TxIsolation", func(t *testing.T) { testTxIsolation(t, newBackend) })
+}
+
+func openDB(t *testing.T, newBackend func() ezdb.Backend) *ezdb.DB {
+	t.Helper()
+	db, err := ezdb.New("", ezdb.WithBackend(newBackend()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func testPointOps(t *testing.T, newBackend func() ezdb.Backend) {
+	db := openDB(t, newBackend)
+	ref, err := ezdb.NewRef[string, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	k, v := "hello", "world"
+	if err := ref.Put(&k, &v); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	out, err := ref.Get(&k)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if *out != v {
+		t.Errorf("Get: expected %q, got %q", v, *out)
+	}
+
+	if err := ref.Delete(&k); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := ref.Get(&k); err != ezdb.ErrNotFound {
+		t.Errorf("Get after Delete: expected ErrNotFound, got %v", err)
+	}
+}
+
+func testBatch(t *testing.T, newBackend func() ezdb.Backend) {
+	db := openDB(t, newBackend)
+	ref, err := ezdb.NewRef[int, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	const n = 50
+	keys := make([]int, n)
+	values := make([]string, n)
+	for i := 0; i < n; i++ {
+		keys[i] = i
+		values[i] = fmt.Sprintf("v%d", i)
+	}
+	if err := ref.PutBatch(keys, values); err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		out, err := ref.Get(&keys[i])
+		if err != nil || *out != values[i] {
+			t.Fatalf("Get(%d) after PutBatch: %v, %v", i, out, err)
+		}
+	}
+
+	if err := ref.DeleteBatch(keys[:n/2]); err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+	for i := 0; i < n/2; i++ {
+		if _, err := ref.Get(&keys[i]); err != ezdb.ErrNotFound {
+			t.Errorf("key %d: expected ErrNotFound after DeleteBatch, got %v", i, err)
+		}
+	}
+
+	if err := ref.BulkDeleteAll(); err != nil {
+		t.Fatalf("BulkDeleteAll: %v", err)
+	}
+	for i := n / 2; i < n; i++ {
+		if _, err := ref.Get(&keys[i]); err != ezdb.ErrNotFound {
+			t.Errorf("key %d: expected ErrNotFound after BulkDeleteAll, got %v", i, err)
+		}
+	}
+}
+
+func testIteration(t *testing.T, newBackend func() ezdb.Backend) {
+	db := openDB(t, newBackend)
+	ref, err := ezdb.NewRef[int, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		v := i * i
+		if err := ref.Put(&i, &v); err != nil {
+			t.Fatalf("Put(%d): %v", i, err)
+		}
+	}
+
+	var got []int
+	err = ref.Iterate(ezdb.IterOptions[int]{}, func(k *int, v *int) error {
+		got = append(got, *k)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Iterate: %v", err)
+	}
+	for i, k := range got {
+		if k != i {
+			t.Fatalf("Iterate: expected ascending order, got %v", got)
+		}
+	}
+	if len(got) != 10 {
+		t.Fatalf("Iterate: expected 10 keys, got %d", len(got))
+	}
+}
+
+func testTxIsolation(t *testing.T, newBackend func() ezdb.Backend) {
+	db := openDB(t, newBackend)
+	ref, err := ezdb.NewRef[string, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	k, v := "k", 1
+	if err := ref.Put(&k, &v); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	// The reader's View is held open across the writer's commit below by
+	// sleeping rather than by blocking on a channel the writer signals:
+	// on the bbolt backend, a commit that needs to grow the mmap blocks
+	// until every open read transaction finishes, so making the writer's
+	// completion a precondition for ending the read transaction would
+	// deadlock the two goroutines against each other.
+	var wg sync.WaitGroup
+	started := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = db.View(func(tx *ezdb.Tx) error {
+			rt := ref.Tx(tx)
+			first, err := rt.Get(&k)
+			if err != nil {
+				return err
+			}
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+			second, err := rt.Get(&k)
+			if err != nil {
+				return err
+			}
+			if *first != *second {
+				t.Errorf("repeatable read violated: first=%d second=%d", *first, *second)
+			}
+			return nil
+		})
+	}()
+
+	<-started
+	v2 := 2
+	if err := ref.Put(&k, &v2); err != nil {
+		t.Fatalf("concurrent Put: %v", err)
+	}
+	wg.Wait()
+
+	out, err := ref.Get(&k)
+	if err != nil || *out != v2 {
+		t.Fatalf("expected committed write visible after Update, got %v, %v", out, err)
+	}
+}
@@ -0,0 +1,287 @@
+package dbtest
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kokizzu/ezdb"
+)
+
+// Op identifies the kind of operation a concurrent workload worker may
+// perform.
+type Op int
+
+const (
+	OpRead Op = iota
+	OpWrite
+	OpDelete
+)
+
+// OpChance is one entry of a weighted choice table: Op is picked with
+// probability proportional to Weight among the other entries in the same
+// ConcurrentConfig.Ops.
+type OpChance struct {
+	Op     Op
+	Weight int
+}
+
+// ConcurrentConfig parameterizes RunConcurrentWorkload.
+type ConcurrentConfig struct {
+	// NumRefs is the number of refs ("buckets") the workload spreads
+	// across.
+	NumRefs int
+	// KeysPerRef is the number of distinct keys used within each ref.
+	KeysPerRef int
+	// Goroutines is the number of concurrent workers.
+	Goroutines int
+	// MinWait and MaxWait bound how long a worker sleeps between
+	// operations; a random value in [MinWait, MaxWait) is used each time
+	// (MinWait when the two are equal, and no sleep when MaxWait <= 0).
+	MinWait time.Duration
+	MaxWait time.Duration
+	// Ops is the weighted choice table workers draw their next operation
+	// from.
+	Ops []OpChance
+	// MinValueSize and MaxValueSize bound the size of values written by
+	// OpWrite.
+	MinValueSize int
+	MaxValueSize int
+	// Duration is how long the workload runs before workers are asked to
+	// stop.
+	Duration time.Duration
+}
+
+// DurationFromEnv parses the named environment variable as a
+// time.Duration, falling back to fallback if it is unset or unparsable.
+// This lets a workload's run length be tuned without recompiling, e.g.
+// EZDB_CONCURRENT_DURATION=5s go test -race ./...
+func DurationFromEnv(env string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(env)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return fallback
+	}
+	return d
+}
+
+// writeEvent is one committed write or delete recorded by the oracle,
+// tagged with the global commit sequence number it was assigned while
+// holding the DB's single writer lock. Because that number is assigned
+// from inside the Update callback, ordering by it reconstructs the true
+// commit order even though workers call Put/Delete concurrently.
+type writeEvent struct {
+	seq     int64
+	deleted bool
+	value   []byte
+}
+
+type oracleKey struct {
+	ref int
+	key int
+}
+
+// RunConcurrentWorkload drives cfg.Goroutines workers, each repeatedly
+// picking a ref/key pair and an operation via cfg.Ops, against db for
+// cfg.Duration. Every committed write is recorded into an in-memory
+// oracle tagged with its true commit order; once the workload stops, the
+// oracle is replayed and the last write to each key is compared against
+// the DB's actual state. It also spot-checks, while workers are still
+// running, that a read transaction's repeated Gets of the same key never
+// disagree with each other (see db.View's repeatable-read guarantee).
+//
+// RunConcurrentWorkload creates its own refs (named "bucket0".."bucketN-1"
+// with int keys and []byte values) inside db, so callers should pass a
+// DB dedicated to this call.
+func RunConcurrentWorkload(t *testing.T, db *ezdb.DB, cfg ConcurrentConfig) {
+	t.Helper()
+
+	refs := make([]*ezdb.Ref[int, []byte], cfg.NumRefs)
+	for i := range refs {
+		ref, err := ezdb.NewRef[int, []byte](fmt.Sprintf("bucket%d", i), db)
+		if err != nil {
+			t.Fatalf("NewRef(bucket%d): %v", i, err)
+		}
+		refs[i] = ref
+	}
+
+	totalWeight := 0
+	for _, w := range cfg.Ops {
+		totalWeight += w.Weight
+	}
+
+	var seq atomic.Int64
+	var oracleMu sync.Mutex
+	oracle := map[oracleKey][]writeEvent{}
+	record := func(refIdx, key int, ev writeEvent) {
+		oracleMu.Lock()
+		k := oracleKey{refIdx, key}
+		oracle[k] = append(oracle[k], ev)
+		oracleMu.Unlock()
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for g := 0; g < cfg.Goroutines; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				refIdx := rnd.Intn(cfg.NumRefs)
+				key := rnd.Intn(cfg.KeysPerRef)
+				ref := refs[refIdx]
+
+				switch pickOp(rnd, cfg.Ops, totalWeight) {
+				case OpRead:
+					if _, err := ref.Get(&key); err != nil && err != ezdb.ErrNotFound {
+						t.Errorf("Get(ref=%d, key=%d): %v", refIdx, key, err)
+					}
+				case OpWrite:
+					value := randomValue(rnd, cfg.MinValueSize, cfg.MaxValueSize)
+					var s int64
+					err := db.Update(func(tx *ezdb.Tx) error {
+						s = seq.Add(1)
+						return ref.Tx(tx).Put(&key, &value)
+					})
+					if err != nil {
+						t.Errorf("Put(ref=%d, key=%d): %v", refIdx, key, err)
+						continue
+					}
+					record(refIdx, key, writeEvent{seq: s, value: value})
+				case OpDelete:
+					var s int64
+					err := db.Update(func(tx *ezdb.Tx) error {
+						s = seq.Add(1)
+						return ref.Tx(tx).Delete(&key)
+					})
+					if err != nil {
+						t.Errorf("Delete(ref=%d, key=%d): %v", refIdx, key, err)
+						continue
+					}
+					record(refIdx, key, writeEvent{seq: s, deleted: true})
+				}
+
+				sleepBetween(rnd, cfg.MinWait, cfg.MaxWait)
+			}
+		}(int64(g))
+	}
+
+	// A dedicated checker verifies the repeatable-read invariant while
+	// the workers above are still mutating the store: two Gets of the
+	// same key inside one View must never disagree with each other.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rnd := rand.New(rand.NewSource(1))
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			refIdx := rnd.Intn(cfg.NumRefs)
+			key := rnd.Intn(cfg.KeysPerRef)
+			ref := refs[refIdx]
+			_ = db.View(func(tx *ezdb.Tx) error {
+				rt := ref.Tx(tx)
+				first, errFirst := rt.Get(&key)
+				time.Sleep(time.Millisecond)
+				second, errSecond := rt.Get(&key)
+				if (errFirst == nil) != (errSecond == nil) {
+					t.Errorf("repeatable-read invariant violated: ref=%d key=%d existence changed within one Tx", refIdx, key)
+					return nil
+				}
+				if errFirst == nil && !bytes.Equal(*first, *second) {
+					t.Errorf("repeatable-read invariant violated: ref=%d key=%d value changed within one Tx", refIdx, key)
+				}
+				return nil
+			})
+		}
+	}()
+
+	time.Sleep(cfg.Duration)
+	close(stop)
+	wg.Wait()
+
+	verifyOracle(t, refs, oracle)
+}
+
+func pickOp(rnd *rand.Rand, ops []OpChance, totalWeight int) Op {
+	if totalWeight <= 0 {
+		return OpRead
+	}
+	n := rnd.Intn(totalWeight)
+	for _, w := range ops {
+		if n < w.Weight {
+			return w.Op
+		}
+		n -= w.Weight
+	}
+	return ops[len(ops)-1].Op
+}
+
+func randomValue(rnd *rand.Rand, minSize, maxSize int) []byte {
+	size := minSize
+	if maxSize > minSize {
+		size += rnd.Intn(maxSize - minSize)
+	}
+	v := make([]byte, size)
+	rnd.Read(v)
+	return v
+}
+
+func sleepBetween(rnd *rand.Rand, minWait, maxWait time.Duration) {
+	if maxWait <= 0 {
+		return
+	}
+	wait := minWait
+	if maxWait > minWait {
+		wait += time.Duration(rnd.Int63n(int64(maxWait - minWait)))
+	}
+	time.Sleep(wait)
+}
+
+// verifyOracle replays each key's recorded writes in true commit order
+// and checks the DB's current state matches the last one.
+func verifyOracle(t *testing.T, refs []*ezdb.Ref[int, []byte], oracle map[oracleKey][]writeEvent) {
+	t.Helper()
+	for k, events := range oracle {
+		sort.Slice(events, func(i, j int) bool { return events[i].seq < events[j].seq })
+		last := events[len(events)-1]
+
+		ref := refs[k.ref]
+		key := k.key
+		got, err := ref.Get(&key)
+
+		if last.deleted {
+			if err != ezdb.ErrNotFound {
+				t.Errorf("oracle mismatch: ref=%d key=%d: expected deleted, got value=%v err=%v", k.ref, key, got, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("oracle mismatch: ref=%d key=%d: expected %x, got error %v", k.ref, key, last.value, err)
+			continue
+		}
+		if !bytes.Equal(*got, last.value) {
+			t.Errorf("oracle mismatch: ref=%d key=%d: expected %x, got %x", k.ref, key, last.value, *got)
+		}
+	}
+}
@@ -1,13 +1,16 @@
-package ezdb
+package ezdb_test
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
+
+	"github.com/kokizzu/ezdb"
+	"github.com/kokizzu/ezdb/dbtest"
 )
 
 // TestEZDB_BasicOperations tests basic put and get operations.
@@ -17,14 +20,14 @@ func TestEZDB_BasicOperations(t *testing.T) {
 	defer os.RemoveAll("testdb_basic")
 
 	// Create a new database client.
-	db, err := New("testdb_basic")
+	db, err := ezdb.New("testdb_basic")
 	if err != nil {
 		t.Fatalf("Failed to create new db client: %v", err)
 	}
 	defer db.Close()
 
 	// Create a new database reference.
-	ref, err := NewRef[string, string]("test_ref", db)
+	ref, err := ezdb.NewRef[string, string]("test_ref", db)
 	if err != nil {
 		t.Fatalf("Failed to create new db ref: %v", err)
 	}
@@ -57,14 +60,14 @@ func TestEZDB_ConcurrentAccess(t *testing.T) {
 	defer os.RemoveAll("testdb_concurrent")
 
 	// Create a new database client with increased number of readers.
-	db, err := New("testdb_concurrent", WithNumReaders(100))
+	db, err := ezdb.New("testdb_concurrent", ezdb.WithNumReaders(100))
 	if err != nil {
 		t.Fatalf("Failed to create new db client: %v", err)
 	}
 	defer db.Close()
 
 	// Create a new database reference.
-	ref, err := NewRef[int, string]("test_ref_concurrent", db)
+	ref, err := ezdb.NewRef[int, string]("test_ref_concurrent", db)
 	if err != nil {
 		t.Fatalf("Failed to create new db ref: %v", err)
 	}
@@ -116,54 +119,34 @@ func TestEZDB_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 }
 
-// TestEZDB_StressTest performs a stress test with heavy concurrent operations.
+// TestEZDB_StressTest performs a stress test with heavy concurrent
+// operations, using dbtest's configurable concurrent workload harness
+// (see dbtest.RunConcurrentWorkload) instead of a bespoke goroutine loop.
+// Its duration can be overridden via EZDB_CONCURRENT_DURATION, e.g. to run
+// it longer under `go test -race`.
 func TestEZDB_StressTest(t *testing.T) {
 	os.RemoveAll("testdb_stress")
 	defer os.RemoveAll("testdb_stress")
 
-	db, err := New("testdb_stress", WithNumReaders(100))
+	db, err := ezdb.New("testdb_stress", ezdb.WithNumReaders(100))
 	if err != nil {
 		t.Fatalf("Failed to create new db client: %v", err)
 	}
 	defer db.Close()
 
-	ref, err := NewRef[int, []byte]("test_ref_stress", db)
-	if err != nil {
-		t.Fatalf("Failed to create new db ref: %v", err)
-	}
-
-	var wg sync.WaitGroup
-	numGoroutines := 100
-	numOperations := 1000
-
-	// Stress test with concurrent writes and reads.
-	for i := 0; i < numGoroutines; i++ {
-		wg.Add(1)
-		i := i // Capture loop variable.
-		go func() {
-			defer wg.Done()
-			for j := 0; j < numOperations; j++ {
-				key := i*numOperations + j
-				value := bytes.Repeat([]byte{byte(key % 256)}, 1024) // 1KB value.
-				err := ref.Put(&key, &value)
-				if err != nil {
-					t.Errorf("Failed to put key %d: %v", key, err)
-				}
-
-				// Optionally test reads.
-				valueOut, err := ref.Get(&key)
-				if err != nil {
-					t.Errorf("Failed to get key %d: %v", key, err)
-					continue
-				}
-				if !bytes.Equal(*valueOut, value) {
-					t.Errorf("For key %d, values do not match", key)
-				}
-			}
-		}()
-	}
-
-	wg.Wait()
+	dbtest.RunConcurrentWorkload(t, db, dbtest.ConcurrentConfig{
+		NumRefs:    1,
+		KeysPerRef: 100 * 1000,
+		Goroutines: 100,
+		Ops: []dbtest.OpChance{
+			{Op: dbtest.OpRead, Weight: 60},
+			{Op: dbtest.OpWrite, Weight: 30},
+			{Op: dbtest.OpDelete, Weight: 10},
+		},
+		MinValueSize: 1024,
+		MaxValueSize: 1024,
+		Duration:     dbtest.DurationFromEnv("EZDB_CONCURRENT_DURATION", 2*time.Second),
+	})
 }
 
 // FuzzEZDB performs fuzz testing on the database operations.
@@ -172,13 +155,13 @@ func FuzzEZDB(f *testing.F) {
 	os.RemoveAll("testdb_fuzz")
 	defer os.RemoveAll("testdb_fuzz")
 
-	db, err := New("testdb_fuzz", WithLogger(zerolog.Nop()))
+	db, err := ezdb.New("testdb_fuzz", ezdb.WithLogger(zerolog.Nop()))
 	if err != nil {
 		f.Fatalf("Failed to create new db client: %v", err)
 	}
 	defer db.Close()
 
-	ref, err := NewRef[string, string]("test_ref_fuzz", db)
+	ref, err := ezdb.NewRef[string, string]("test_ref_fuzz", db)
 	if err != nil {
 		f.Fatalf("Failed to create new db ref: %v", err)
 	}
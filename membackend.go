@@ -0,0 +1,267 @@
+package ezdb
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemBackend is an in-memory Backend, useful for tests that want to
+// exercise ezdb without touching the filesystem. It mirrors bbolt's
+// concurrency model: any number of read transactions may run
+// concurrently against a stable snapshot, but only one write transaction
+// may be open at a time, and its writes are invisible to readers until
+// it commits.
+type MemBackend struct {
+	writeMu sync.Mutex
+	buckets sync.Map // map[string]*memBucket
+}
+
+// NewMemBackend returns a ready-to-use, empty MemBackend.
+func NewMemBackend() *MemBackend {
+	return &MemBackend{}
+}
+
+func (b *MemBackend) bucket(name string) *memBucket {
+	v, _ := b.buckets.LoadOrStore(name, &memBucket{data: map[string][]byte{}})
+	return v.(*memBucket)
+}
+
+func (b *MemBackend) Bucket(name string) error {
+	b.bucket(name)
+	return nil
+}
+
+func (b *MemBackend) Close() error { return nil }
+
+func (b *MemBackend) BeginTx(writable bool) (BackendTx, error) {
+	if writable {
+		b.writeMu.Lock()
+		return &memTx{backend: b, writable: true, pending: map[string]*memPending{}}, nil
+	}
+
+	snapshot := map[string]map[string][]byte{}
+	b.buckets.Range(func(k, v any) bool {
+		mb := v.(*memBucket)
+		mb.mu.RLock()
+		clone := make(map[string][]byte, len(mb.data))
+		for kk, vv := range mb.data {
+			clone[kk] = vv
+		}
+		mb.mu.RUnlock()
+		snapshot[k.(string)] = clone
+		return true
+	})
+	return &memTx{backend: b, writable: false, snapshot: snapshot}, nil
+}
+
+// memPending buffers a writable tx's changes to one bucket so they stay
+// invisible to concurrent readers (and to the bucket's live data) until
+// Commit.
+type memPending struct {
+	set       map[string][]byte
+	del       map[string]bool
+	truncated bool
+}
+
+func newMemPending() *memPending {
+	return &memPending{set: map[string][]byte{}, del: map[string]bool{}}
+}
+
+type memTx struct {
+	backend  *MemBackend
+	writable bool
+	snapshot map[string]map[string][]byte // read-only tx only
+	pending  map[string]*memPending       // writable tx only
+	done     bool
+}
+
+func (t *memTx) Bucket(name string) BackendBucket {
+	if t.writable {
+		p, ok := t.pending[name]
+		if !ok {
+			p = newMemPending()
+			t.pending[name] = p
+		}
+		return &memTxBucket{tx: t, name: name, pending: p}
+	}
+	return &memTxBucket{tx: t, name: name, base: t.snapshot[name]}
+}
+
+func (t *memTx) Writable() bool { return t.writable }
+
+func (t *memTx) Commit() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	defer t.backend.writeMu.Unlock()
+
+	for name, p := range t.pending {
+		mb := t.backend.bucket(name)
+		mb.mu.Lock()
+		if p.truncated {
+			mb.data = map[string][]byte{}
+		}
+		for k := range p.del {
+			delete(mb.data, k)
+		}
+		for k, v := range p.set {
+			mb.data[k] = v
+		}
+		mb.mu.Unlock()
+	}
+	return nil
+}
+
+func (t *memTx) Rollback() error {
+	if t.done {
+		return nil
+	}
+	t.done = true
+	if t.writable {
+		t.backend.writeMu.Unlock()
+	}
+	return nil
+}
+
+type memBucket struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// memTxBucket is a single bucket as seen from within a memTx: base (a
+// read-only snapshot) for read transactions, or pending (an overlay of
+// not-yet-committed changes on top of the live bucket) for write
+// transactions.
+type memTxBucket struct {
+	tx      *memTx
+	name    string
+	base    map[string][]byte
+	pending *memPending
+}
+
+func (b *memTxBucket) Get(key []byte) []byte {
+	k := string(key)
+	if b.pending != nil {
+		if b.pending.del[k] {
+			return nil
+		}
+		if v, ok := b.pending.set[k]; ok {
+			return append([]byte(nil), v...)
+		}
+		if b.pending.truncated {
+			return nil
+		}
+		mb := b.tx.backend.bucket(b.name)
+		mb.mu.RLock()
+		v, ok := mb.data[k]
+		mb.mu.RUnlock()
+		if !ok {
+			return nil
+		}
+		return append([]byte(nil), v...)
+	}
+	v, ok := b.base[k]
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), v...)
+}
+
+func (b *memTxBucket) Put(key, value []byte) error {
+	if b.pending == nil {
+		return errReadOnlyBackendTx
+	}
+	k := string(key)
+	delete(b.pending.del, k)
+	b.pending.set[k] = append([]byte(nil), value...)
+	return nil
+}
+
+func (b *memTxBucket) Delete(key []byte) error {
+	if b.pending == nil {
+		return errReadOnlyBackendTx
+	}
+	k := string(key)
+	delete(b.pending.set, k)
+	b.pending.del[k] = true
+	return nil
+}
+
+func (b *memTxBucket) DeleteAll() error {
+	if b.pending == nil {
+		return errReadOnlyBackendTx
+	}
+	b.pending.truncated = true
+	b.pending.set = map[string][]byte{}
+	b.pending.del = map[string]bool{}
+	return nil
+}
+
+// merged returns the bucket's sorted keys and values as seen by this tx.
+func (b *memTxBucket) merged() ([]string, map[string][]byte) {
+	var view map[string][]byte
+	if b.pending != nil {
+		view = map[string][]byte{}
+		if !b.pending.truncated {
+			mb := b.tx.backend.bucket(b.name)
+			mb.mu.RLock()
+			for k, v := range mb.data {
+				view[k] = v
+			}
+			mb.mu.RUnlock()
+		}
+		for k := range b.pending.del {
+			delete(view, k)
+		}
+		for k, v := range b.pending.set {
+			view[k] = v
+		}
+	} else {
+		view = b.base
+	}
+
+	keys := make([]string, 0, len(view))
+	for k := range view {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, view
+}
+
+func (b *memTxBucket) Cursor() BackendCursor {
+	keys, view := b.merged()
+	return &memCursor{keys: keys, view: view, pos: -1}
+}
+
+var errReadOnlyBackendTx = &backendError{"ezdb: write attempted on a read-only backend transaction"}
+
+type backendError struct{ msg string }
+
+func (e *backendError) Error() string { return e.msg }
+
+type memCursor struct {
+	keys []string
+	view map[string][]byte
+	pos  int // index of the last key returned, -1 before First/Last is called
+}
+
+func (c *memCursor) at(i int) (k, v []byte) {
+	if i < 0 || i >= len(c.keys) {
+		return nil, nil
+	}
+	c.pos = i
+	key := c.keys[i]
+	return []byte(key), c.view[key]
+}
+
+func (c *memCursor) First() (k, v []byte) { return c.at(0) }
+func (c *memCursor) Last() (k, v []byte)  { return c.at(len(c.keys) - 1) }
+func (c *memCursor) Next() (k, v []byte)  { return c.at(c.pos + 1) }
+func (c *memCursor) Prev() (k, v []byte)  { return c.at(c.pos - 1) }
+
+func (c *memCursor) Seek(key []byte) (k, v []byte) {
+	target := string(key)
+	i := sort.SearchStrings(c.keys, target)
+	return c.at(i)
+}
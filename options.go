@@ -0,0 +1,63 @@
+package ezdb
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// options holds the configuration assembled from Option values passed to New.
+type options struct {
+	numReaders int
+	logger     zerolog.Logger
+	timeout    time.Duration
+	backend    Backend
+}
+
+func defaultOptions() options {
+	return options{
+		numReaders: 16,
+		logger:     zerolog.Nop(),
+		timeout:    2 * time.Second,
+	}
+}
+
+// Option configures a DB at construction time.
+type Option func(*options)
+
+// WithNumReaders caps the number of read transactions allowed to run
+// concurrently against the DB. It is enforced in-process (bbolt itself has
+// no reader limit) and exists to bound memory/goroutine pressure under
+// heavy concurrent View load.
+func WithNumReaders(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.numReaders = n
+		}
+	}
+}
+
+// WithLogger sets the logger used for diagnostic messages, such as the
+// Tx finalizer warning (see Tx).
+func WithLogger(logger zerolog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithOpenTimeout bounds how long New waits to acquire the on-disk file
+// lock before giving up.
+func WithOpenTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithBackend selects the storage engine New uses instead of the default
+// on-disk bbolt backend. See MemBackend for an in-memory implementation
+// useful in tests.
+func WithBackend(b Backend) Option {
+	return func(o *options) {
+		o.backend = b
+	}
+}
@@ -0,0 +1,74 @@
+package ezdb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned by Get (and RefTx.Get) when the requested key
+// does not exist in the ref.
+var ErrNotFound = errors.New("ezdb: key not found")
+
+// Ref is a typed, generic handle onto a single named keyspace within a
+// DB. K and V are encoded with codec.go's encodeKey / encodeValue and
+// decodeValue respectively.
+type Ref[K any, V any] struct {
+	db   *DB
+	name string
+}
+
+// NewRef opens (creating if necessary) the named ref within db.
+func NewRef[K any, V any](name string, db *DB) (*Ref[K, V], error) {
+	if err := db.backend.Bucket(name); err != nil {
+		return nil, fmt.Errorf("ezdb: create ref %q: %w", name, err)
+	}
+	return &Ref[K, V]{db: db, name: name}, nil
+}
+
+// Put stores value under key, overwriting any existing entry.
+func (r *Ref[K, V]) Put(key *K, value *V) error {
+	kb, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	vb, err := encodeValue(value)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *Tx) error {
+		return tx.backend.Bucket(r.name).Put(kb, vb)
+	})
+}
+
+// Get looks up key and returns its value, or ErrNotFound if it is absent.
+func (r *Ref[K, V]) Get(key *K) (*V, error) {
+	kb, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []byte
+	err = r.db.View(func(tx *Tx) error {
+		v := tx.backend.Bucket(r.name).Get(kb)
+		if v == nil {
+			return ErrNotFound
+		}
+		raw = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decodeValue[V](raw)
+}
+
+// Delete removes key. It is not an error for key to be absent.
+func (r *Ref[K, V]) Delete(key *K) error {
+	kb, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *Tx) error {
+		return tx.backend.Bucket(r.name).Delete(kb)
+	})
+}
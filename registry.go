@@ -0,0 +1,121 @@
+package ezdb
+
+import "sync"
+
+// registryEntry tracks the shared *DB for one path and how many callers
+// currently hold a reference to it. mu serializes every Open and Close on
+// this path, so a late Open blocks until an in-progress Close has finished
+// tearing down the backend, and two racing first-time Opens never both
+// construct a backend for the same path.
+type registryEntry struct {
+	mu   sync.Mutex
+	db   *DB
+	refs int
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*registryEntry{}
+)
+
+// registryOpenHook and registryCloseHook, when non-nil, are called exactly
+// once per actual backend open/close performed by the registry (as opposed
+// to once per Open/Close call). Tests use them to observe that at most one
+// backend is ever live for a given path at a time.
+var (
+	registryOpenHook  func(path string)
+	registryCloseHook func(path string)
+)
+
+// Open returns a *DB for path, sharing one underlying connection across
+// every caller that opens the same path concurrently. The first Open for a
+// path creates the backend exactly as New would, passing opts through;
+// later concurrent Opens for the same path reuse it and ignore opts.
+//
+// Each successful Open must be balanced by exactly one Close. The backend
+// is only actually closed once the last outstanding reference is released,
+// and a Close that is still shutting down the backend blocks any Open for
+// the same path until that shutdown completes.
+func Open(path string, opts ...Option) (*DB, error) {
+	for {
+		registryMu.Lock()
+		e, ok := registry[path]
+		if !ok {
+			e = &registryEntry{}
+			registry[path] = e
+		}
+		registryMu.Unlock()
+
+		e.mu.Lock()
+
+		// closeRegistered removes an entry from the registry before
+		// releasing e.mu once its refcount drops to zero. If that raced
+		// with the lookup above, e is a stale, unregistered entry: retry
+		// with a fresh lookup instead of reviving it, or a concurrent
+		// Open could end up holding a *DB this registry no longer tracks.
+		registryMu.Lock()
+		current := registry[path] == e
+		registryMu.Unlock()
+		if !current {
+			e.mu.Unlock()
+			continue
+		}
+
+		if e.db == nil {
+			db, err := New(path, opts...)
+			if err != nil {
+				e.mu.Unlock()
+				return nil, err
+			}
+			db.refPath = path
+			e.db = db
+			if registryOpenHook != nil {
+				registryOpenHook(path)
+			}
+		}
+		e.refs++
+		e.mu.Unlock()
+		return e.db, nil
+	}
+}
+
+// closeRegistered releases one reference obtained via Open for path,
+// closing the backend and removing path's entry from the registry once
+// the reference count drops to zero. Without the removal, a long-running
+// process that opens and closes many distinct paths over its lifetime
+// would leak one registryEntry per distinct path forever.
+func closeRegistered(path string) error {
+	registryMu.Lock()
+	e, ok := registry[path]
+	registryMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.db == nil {
+		// Already fully closed; nothing left to release.
+		return nil
+	}
+
+	e.refs--
+	if e.refs > 0 {
+		return nil
+	}
+
+	err := e.db.backend.Close()
+	e.db = nil
+	if registryCloseHook != nil {
+		registryCloseHook(path)
+	}
+
+	registryMu.Lock()
+	if registry[path] == e {
+		delete(registry, path)
+	}
+	registryMu.Unlock()
+
+	return err
+}
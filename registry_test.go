@@ -0,0 +1,140 @@
+package ezdb
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestRegistry_OpenCloseConcurrent spins many goroutines each calling
+// Open/Close on the same path in a loop, and uses the registry's
+// open/close hooks to assert that at most one backend is ever live for
+// that path at a time, and that none is left open once every caller has
+// released its reference.
+func TestRegistry_OpenCloseConcurrent(t *testing.T) {
+	const path = "registry-test-path"
+
+	var live, maxLive atomic.Int32
+
+	prevOpen, prevClose := registryOpenHook, registryCloseHook
+	registryOpenHook = func(string) {
+		n := live.Add(1)
+		for {
+			m := maxLive.Load()
+			if n <= m || maxLive.CompareAndSwap(m, n) {
+				break
+			}
+		}
+	}
+	registryCloseHook = func(string) {
+		live.Add(-1)
+	}
+	defer func() {
+		registryOpenHook, registryCloseHook = prevOpen, prevClose
+	}()
+
+	const goroutines = 20
+	const iterations = 50
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				db, err := Open(path, WithBackend(NewMemBackend()))
+				if err != nil {
+					t.Errorf("Open: %v", err)
+					return
+				}
+				if err := db.Close(); err != nil {
+					t.Errorf("Close: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := live.Load(); got != 0 {
+		t.Fatalf("expected 0 live backends once all callers closed, got %d", got)
+	}
+	if got := maxLive.Load(); got > 1 {
+		t.Fatalf("expected at most 1 live backend at a time, observed %d", got)
+	}
+}
+
+// TestRegistry_SharesInstance checks that concurrent Opens of the same
+// path that overlap in time get back the same *DB, and that the path can
+// be opened again once every one of them has called Close.
+func TestRegistry_SharesInstance(t *testing.T) {
+	const path = "registry-test-shared"
+
+	const n = 10
+	dbs := make([]*DB, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			db, err := Open(path, WithBackend(NewMemBackend()))
+			if err != nil {
+				t.Errorf("Open: %v", err)
+				return
+			}
+			dbs[i] = db
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i < n; i++ {
+		if dbs[i] != dbs[0] {
+			t.Fatalf("Open returned distinct *DB for overlapping callers: dbs[0]=%p dbs[%d]=%p", dbs[0], i, dbs[i])
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if err := dbs[i].Close(); err != nil {
+			t.Fatalf("Close %d: %v", i, err)
+		}
+	}
+
+	db2, err := Open(path, WithBackend(NewMemBackend()))
+	if err != nil {
+		t.Fatalf("Open after full close: %v", err)
+	}
+	if err := db2.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// TestRegistry_RemovesEntryOnFullClose verifies that a path's
+// registryEntry is removed from the registry once its last reference is
+// closed, so a long-running process opening and closing many distinct
+// paths doesn't leak one entry per path forever.
+func TestRegistry_RemovesEntryOnFullClose(t *testing.T) {
+	const path = "registry-test-gc"
+
+	db, err := Open(path, WithBackend(NewMemBackend()))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	registryMu.Lock()
+	_, present := registry[path]
+	registryMu.Unlock()
+	if !present {
+		t.Fatalf("expected registry entry to exist while open")
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	registryMu.Lock()
+	_, present = registry[path]
+	registryMu.Unlock()
+	if present {
+		t.Fatalf("expected registry entry to be removed once refcount reached 0")
+	}
+}
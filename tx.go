@@ -0,0 +1,211 @@
+package ezdb
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// ErrNestedUpdate is returned when Update is called reentrantly from
+// within an already-running Update on the same DB and goroutine. Without
+// this check the recursive call would deadlock against the backend's
+// single writer lock instead of failing cleanly.
+var ErrNestedUpdate = errors.New("ezdb: nested Update is not supported")
+
+// ErrReadOnlyTx is returned by RefTx.Put and RefTx.Delete when called
+// against a Tx opened by View.
+var ErrReadOnlyTx = errors.New("ezdb: write attempted on a read-only Tx")
+
+// Tx is a handle onto a single backend transaction, shared by every Ref
+// the caller touches inside a View or Update callback.
+//
+// A Tx obtained from View sees a frozen, repeatable-read snapshot of the
+// store for its entire lifetime: two Gets of the same key within the same
+// Tx always return identical bytes, regardless of any Update that commits
+// concurrently. This falls directly out of the backend's MVCC model,
+// since a read transaction pins the data it started with (see
+// boltbackend.go and membackend.go).
+//
+// A Tx obtained from Update applies all of its writes atomically when the
+// Update callback returns nil; an error (or panic) aborts the transaction
+// and none of its writes take effect. Nothing written inside an in-flight
+// Update is visible to any other Tx until Update returns successfully.
+//
+// A Tx must not be retained past the View/Update call that produced it.
+// Each Tx carries a finalizer that logs a warning if it is garbage
+// collected before its View/Update call has closed it. View and Update
+// always close their Tx on return, including on panic, so a Tx retained
+// past its callback is not reported by this warning.
+type Tx struct {
+	backend  BackendTx
+	db       *DB
+	writable bool
+}
+
+func newTx(db *DB, backendTx BackendTx, writable bool) *Tx {
+	tx := &Tx{backend: backendTx, db: db, writable: writable}
+	runtime.SetFinalizer(tx, finalizeLeakedTx)
+	return tx
+}
+
+// closeTx marks tx as finished so the finalizer no longer treats it as
+// leaked, once control returns from the owning View/Update callback.
+func closeTx(tx *Tx) {
+	runtime.SetFinalizer(tx, nil)
+	tx.backend = nil
+}
+
+func finalizeLeakedTx(tx *Tx) {
+	if tx.backend != nil {
+		tx.db.logger.Warn().Msg("ezdb: a Tx was garbage collected without being closed; " +
+			"Tx values must not be retained past their View/Update callback")
+	}
+}
+
+// writerTracker records, per DB, which goroutine (if any) is executing
+// inside Update, so a reentrant call on the same goroutine can be
+// rejected instead of deadlocking.
+type writerTracker struct {
+	gid atomic.Uint64
+}
+
+// View runs fn against a read-only snapshot of the store. The snapshot is
+// stable for the duration of fn: concurrent Updates may commit while fn
+// runs, but fn never observes their effect.
+func (db *DB) View(fn func(tx *Tx) error) error {
+	db.acquireReader()
+	defer db.releaseReader()
+
+	btx, err := db.backend.BeginTx(false)
+	if err != nil {
+		return err
+	}
+	tx := newTx(db, btx, false)
+	defer closeTx(tx)
+
+	// A panic inside fn must still roll back the backend tx before
+	// propagating, or the panicking goroutine leaks the read snapshot it
+	// pinned.
+	defer func() {
+		if p := recover(); p != nil {
+			btx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		btx.Rollback()
+		return err
+	}
+	return btx.Rollback()
+}
+
+// Update runs fn against a read-write transaction. If fn returns nil the
+// transaction commits atomically; any other return value (or a panic)
+// rolls it back. Calling Update reentrantly from within an in-progress
+// Update on the same goroutine returns ErrNestedUpdate rather than
+// deadlocking.
+func (db *DB) Update(fn func(tx *Tx) error) error {
+	gid := currentGoroutineID()
+	if db.writer.gid.Load() == gid {
+		return ErrNestedUpdate
+	}
+
+	btx, err := db.backend.BeginTx(true)
+	if err != nil {
+		return err
+	}
+	db.writer.gid.Store(gid)
+	// Only clear our own id: once Commit releases the backend's writer
+	// lock, another goroutine's Update may already have stored its own.
+	defer db.writer.gid.CompareAndSwap(gid, 0)
+
+	tx := newTx(db, btx, true)
+	defer closeTx(tx)
+
+	// A panic inside fn must still roll back the backend tx (releasing the
+	// backend's single-writer lock) before propagating, or the DB wedges:
+	// every later Update, and Close on the bbolt backend, blocks forever
+	// waiting for a writer that will never finish.
+	defer func() {
+		if p := recover(); p != nil {
+			btx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		btx.Rollback()
+		return err
+	}
+	return btx.Commit()
+}
+
+// currentGoroutineID extracts the calling goroutine's id from its stack
+// trace. It is only ever used to detect reentrant Update calls on the
+// same goroutine; it has no bearing on scheduling or correctness beyond
+// that check.
+func currentGoroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	field := bytes.Fields(buf[:n])[1]
+	id, _ := strconv.ParseUint(string(field), 10, 64)
+	return id
+}
+
+// Tx scopes Ref's point operations to an existing Tx instead of each call
+// opening (and committing) its own transaction. This is what lets
+// multiple refs participate in the same View/Update.
+func (r *Ref[K, V]) Tx(tx *Tx) *RefTx[K, V] {
+	return &RefTx[K, V]{ref: r, tx: tx}
+}
+
+// RefTx is a Ref bound to a specific, caller-supplied Tx.
+type RefTx[K any, V any] struct {
+	ref *Ref[K, V]
+	tx  *Tx
+}
+
+// Get reads key within the bound Tx's snapshot.
+func (rt *RefTx[K, V]) Get(key *K) (*V, error) {
+	kb, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	v := rt.tx.backend.Bucket(rt.ref.name).Get(kb)
+	if v == nil {
+		return nil, ErrNotFound
+	}
+	return decodeValue[V](v)
+}
+
+// Put writes key/value within the bound Tx. The write is only durable
+// once the enclosing Update returns successfully.
+func (rt *RefTx[K, V]) Put(key *K, value *V) error {
+	if !rt.tx.writable {
+		return ErrReadOnlyTx
+	}
+	kb, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	vb, err := encodeValue(value)
+	if err != nil {
+		return err
+	}
+	return rt.tx.backend.Bucket(rt.ref.name).Put(kb, vb)
+}
+
+// Delete removes key within the bound Tx.
+func (rt *RefTx[K, V]) Delete(key *K) error {
+	if !rt.tx.writable {
+		return ErrReadOnlyTx
+	}
+	kb, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	return rt.tx.backend.Bucket(rt.ref.name).Delete(kb)
+}
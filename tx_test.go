@@ -0,0 +1,226 @@
+package ezdb
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTx_RepeatableRead verifies that a View transaction observes a frozen
+// snapshot: repeated Gets of the same key return identical bytes even
+// while a concurrent Update commits new values in between.
+func TestTx_RepeatableRead(t *testing.T) {
+	os.RemoveAll("testdb_tx_repeatable")
+	defer os.RemoveAll("testdb_tx_repeatable")
+
+	db, err := New("testdb_tx_repeatable")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[string, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	key, initial := "k", "v0"
+	if err := ref.Put(&key, &initial); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	readerStarted := make(chan struct{})
+	readerDone := make(chan error, 1)
+
+	go func() {
+		readerDone <- db.View(func(tx *Tx) error {
+			rt := ref.Tx(tx)
+			first, err := rt.Get(&key)
+			if err != nil {
+				return err
+			}
+			close(readerStarted)
+
+			// Give the concurrent Update below a chance to commit.
+			time.Sleep(100 * time.Millisecond)
+
+			second, err := rt.Get(&key)
+			if err != nil {
+				return err
+			}
+			if *first != *second {
+				t.Errorf("repeatable read violated: first=%q second=%q", *first, *second)
+			}
+			if *second != initial {
+				t.Errorf("snapshot leaked a concurrent write: got %q, want %q", *second, initial)
+			}
+			return nil
+		})
+	}()
+
+	<-readerStarted
+	updated := "v1"
+	if err := ref.Put(&key, &updated); err != nil {
+		t.Fatalf("concurrent Put: %v", err)
+	}
+
+	if err := <-readerDone; err != nil {
+		t.Fatalf("View: %v", err)
+	}
+
+	out, err := ref.Get(&key)
+	if err != nil {
+		t.Fatalf("Get after Update: %v", err)
+	}
+	if *out != updated {
+		t.Errorf("expected write to be visible after commit, got %q", *out)
+	}
+}
+
+// TestTx_WriteVisibleOnlyAfterCommit verifies that readers never observe a
+// partial write: a value written inside Update is invisible to concurrent
+// Views until Update returns.
+func TestTx_WriteVisibleOnlyAfterCommit(t *testing.T) {
+	os.RemoveAll("testdb_tx_visibility")
+	defer os.RemoveAll("testdb_tx_visibility")
+
+	db, err := New("testdb_tx_visibility")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[int, int]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var sawPartial atomic.Bool
+
+	writerReady := make(chan struct{})
+	release := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = db.Update(func(tx *Tx) error {
+			rt := ref.Tx(tx)
+			k, v := 1, 42
+			if err := rt.Put(&k, &v); err != nil {
+				return err
+			}
+			close(writerReady)
+			<-release
+			return nil
+		})
+	}()
+
+	<-writerReady
+	k := 1
+	if _, err := ref.Get(&k); err == nil {
+		sawPartial.Store(true)
+	} else if err != ErrNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(release)
+	wg.Wait()
+
+	if sawPartial.Load() {
+		t.Error("a concurrent read observed a write before Update committed")
+	}
+
+	out, err := ref.Get(&k)
+	if err != nil {
+		t.Fatalf("Get after commit: %v", err)
+	}
+	if *out != 42 {
+		t.Errorf("expected 42 after commit, got %d", *out)
+	}
+}
+
+// TestTx_NestedUpdate verifies that calling Update reentrantly from within
+// an in-progress Update on the same goroutine fails fast instead of
+// deadlocking.
+func TestTx_NestedUpdate(t *testing.T) {
+	os.RemoveAll("testdb_tx_nested")
+	defer os.RemoveAll("testdb_tx_nested")
+
+	db, err := New("testdb_tx_nested")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *Tx) error {
+		return db.Update(func(inner *Tx) error {
+			return nil
+		})
+	})
+	if err != ErrNestedUpdate {
+		t.Errorf("expected ErrNestedUpdate, got %v", err)
+	}
+}
+
+// TestTx_PanicRecovers verifies that a panic inside an Update or View
+// callback still rolls back the backend transaction, so a subsequent
+// Update on the same DB succeeds instead of wedging forever on the
+// backend's single-writer lock.
+func TestTx_PanicRecovers(t *testing.T) {
+	os.RemoveAll("testdb_tx_panic")
+	defer os.RemoveAll("testdb_tx_panic")
+
+	db, err := New("testdb_tx_panic")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer db.Close()
+
+	ref, err := NewRef[string, string]("ref", db)
+	if err != nil {
+		t.Fatalf("NewRef: %v", err)
+	}
+
+	func() {
+		defer func() { recover() }()
+		_ = db.Update(func(tx *Tx) error {
+			k, v := "k", "v"
+			if err := ref.Tx(tx).Put(&k, &v); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			panic("boom")
+		})
+	}()
+
+	rolledBackKey := "k"
+	if leaked, err := ref.Get(&rolledBackKey); err != ErrNotFound {
+		t.Fatalf("expected panicking Update's write to be rolled back, got value=%v err=%v", leaked, err)
+	}
+
+	k, v := "k2", "v2"
+	if err := db.Update(func(tx *Tx) error {
+		return ref.Tx(tx).Put(&k, &v)
+	}); err != nil {
+		t.Fatalf("Update after panicking Update: %v", err)
+	}
+
+	if _, err := ref.Get(&k); err != nil {
+		t.Fatalf("Get after recovered panic: %v", err)
+	}
+
+	func() {
+		defer func() { recover() }()
+		_ = db.View(func(tx *Tx) error {
+			panic("boom")
+		})
+	}()
+
+	k3, v3 := "k3", "v3"
+	if err := db.Update(func(tx *Tx) error {
+		return ref.Tx(tx).Put(&k3, &v3)
+	}); err != nil {
+		t.Fatalf("Update after panicking View: %v", err)
+	}
+}